@@ -0,0 +1,253 @@
+// Copyright 2014 The hesiod-go authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hesiod
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// CacheOptions configures the in-process cache installed by
+// NewCachingHesiod.
+type CacheOptions struct {
+	// MaxEntries bounds the number of distinct DNS names held in
+	// the cache; the least recently used entry is evicted once this
+	// is exceeded. Zero means unbounded.
+	MaxEntries int
+	// DefaultTTL is how long a successful lookup is cached, since
+	// net.Resolver.LookupTXT does not expose the record's DNS TTL.
+	DefaultTTL time.Duration
+	// NegativeTTL is how long a failed lookup (e.g. NXDOMAIN) is
+	// cached, to avoid hammering the nameserver for names that
+	// don't exist.
+	NegativeTTL time.Duration
+}
+
+// CacheStats reports cumulative cache activity for a Hesiod created
+// with NewCachingHesiod, suitable for exporting as metrics.
+type CacheStats struct {
+	Hits      int64
+	Misses    int64
+	Evictions int64
+}
+
+// NewCachingHesiod initializes a Hesiod resolver like
+// NewHesiodWithConfig, but memoizes TXT lookups in-process per
+// opts, coalescing concurrent lookups for the same DNS name into a
+// single query.
+//
+// On a cache miss, the returned Hesiod still honors a caller's ctx:
+// the ...Context methods return ctx.Err() promptly if ctx is
+// canceled or expires before the underlying lookup completes.
+// The lookup itself is not aborted in that case; it keeps running in
+// the background so the result is cached for the next caller (and
+// for any other caller already waiting on the same name).
+func NewCachingHesiod(cfg *Config, opts CacheOptions) *Hesiod {
+	hesiod := NewHesiodWithConfig(cfg)
+	hesiod.lookup = newTTLCache(hesiod.lookup, opts)
+	return hesiod
+}
+
+// Purge removes any cached result for the Hesiod query (question,
+// queryType), so that the next Resolve reissues a DNS lookup. Purge
+// is a no-op if hesiod was not created with NewCachingHesiod.
+func (hesiod *Hesiod) Purge(question string, queryType string) error {
+	cache, ok := hesiod.lookup.(*ttlCache)
+	if !ok {
+		return nil
+	}
+	dns, err := hesiod.prepareDNSName(question, queryType)
+	if err != nil {
+		return err
+	}
+	cache.purge(dns)
+	return nil
+}
+
+// Stats returns cache hit/miss/eviction counters for hesiod. It
+// returns the zero CacheStats if hesiod was not created with
+// NewCachingHesiod.
+func (hesiod *Hesiod) Stats() CacheStats {
+	cache, ok := hesiod.lookup.(*ttlCache)
+	if !ok {
+		return CacheStats{}
+	}
+	return cache.stats()
+}
+
+// ttlCache wraps a lookupInterface with a TTL cache and single-flight
+// coalescing of concurrent lookups for the same name.
+type ttlCache struct {
+	inner lookupInterface
+	opts  CacheOptions
+
+	mu      sync.Mutex
+	entries map[string]*list.Element // name -> element of lru
+	lru     *list.List               // of *cacheEntry, most recently used at front
+	stats_  CacheStats
+
+	flightMu sync.Mutex
+	flight   map[string]*cacheCall
+}
+
+type cacheEntry struct {
+	name    string
+	txt     []string
+	err     error
+	expires time.Time
+}
+
+type cacheCall struct {
+	done chan struct{}
+	txt  []string
+	err  error
+}
+
+func newTTLCache(inner lookupInterface, opts CacheOptions) *ttlCache {
+	return &ttlCache{
+		inner:   inner,
+		opts:    opts,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+		flight:  make(map[string]*cacheCall),
+	}
+}
+
+func (c *ttlCache) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	if txt, err, ok := c.get(name); ok {
+		return txt, err
+	}
+	call := c.startCall(name)
+	select {
+	case <-call.done:
+		return call.txt, call.err
+	case <-ctx.Done():
+		// The underlying lookup is left running in the background
+		// (see startCall) so that it still populates the cache for
+		// other callers and for a future retry; only this caller's
+		// wait is abandoned.
+		return nil, ctx.Err()
+	}
+}
+
+func (c *ttlCache) get(name string) (txt []string, err error, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, found := c.entries[name]
+	if !found {
+		c.stats_.Misses++
+		return nil, nil, false
+	}
+	entry := elem.Value.(*cacheEntry)
+	if time.Now().After(entry.expires) {
+		c.removeLocked(elem)
+		c.stats_.Misses++
+		return nil, nil, false
+	}
+	c.stats_.Hits++
+	c.lru.MoveToFront(elem)
+	return entry.txt, entry.err, true
+}
+
+// startCall returns the in-flight cacheCall for name, issuing the
+// underlying lookup in a new goroutine if one isn't already running.
+// This coalesces concurrent callers for the same name into a single
+// call to c.inner. The lookup itself, and the resulting cache.set, is
+// deliberately driven by context.Background() rather than any one
+// caller's ctx and runs to completion independently of its callers:
+// the result (and any error) is shared with, and cached for, every
+// concurrent caller, so one caller's cancellation or timeout must not
+// abort the lookup, poison the cache, or hold up the other waiters.
+func (c *ttlCache) startCall(name string) *cacheCall {
+	c.flightMu.Lock()
+	if call, ok := c.flight[name]; ok {
+		c.flightMu.Unlock()
+		return call
+	}
+	// A lookup for name may have finished and been cached since the
+	// caller's cache miss; share that result instead of re-querying.
+	c.mu.Lock()
+	if elem, ok := c.entries[name]; ok && time.Now().Before(elem.Value.(*cacheEntry).expires) {
+		entry := elem.Value.(*cacheEntry)
+		c.mu.Unlock()
+		c.flightMu.Unlock()
+		call := &cacheCall{done: make(chan struct{}), txt: entry.txt, err: entry.err}
+		close(call.done)
+		return call
+	}
+	c.mu.Unlock()
+	call := &cacheCall{done: make(chan struct{})}
+	c.flight[name] = call
+	c.flightMu.Unlock()
+
+	go func() {
+		call.txt, call.err = c.inner.LookupTXT(context.Background(), name)
+
+		ttl := c.opts.DefaultTTL
+		if call.err != nil {
+			ttl = c.opts.NegativeTTL
+		}
+		c.set(name, call.txt, call.err, ttl)
+
+		c.flightMu.Lock()
+		delete(c.flight, name)
+		c.flightMu.Unlock()
+		close(call.done)
+	}()
+	return call
+}
+
+func (c *ttlCache) set(name string, txt []string, err error, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[name]; ok {
+		c.removeLocked(elem)
+	}
+	elem := c.lru.PushFront(&cacheEntry{name: name, txt: txt, err: err, expires: time.Now().Add(ttl)})
+	c.entries[name] = elem
+	if c.opts.MaxEntries > 0 {
+		for len(c.entries) > c.opts.MaxEntries {
+			oldest := c.lru.Back()
+			if oldest == nil {
+				break
+			}
+			c.removeLocked(oldest)
+			c.stats_.Evictions++
+		}
+	}
+}
+
+func (c *ttlCache) purge(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[name]; ok {
+		c.removeLocked(elem)
+	}
+}
+
+// removeLocked removes elem from the cache. c.mu must be held.
+func (c *ttlCache) removeLocked(elem *list.Element) {
+	entry := elem.Value.(*cacheEntry)
+	delete(c.entries, entry.name)
+	c.lru.Remove(elem)
+}
+
+func (c *ttlCache) stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats_
+}
@@ -0,0 +1,263 @@
+// Copyright 2014 The hesiod-go authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hesiod
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingResolver wraps a mockResolver and counts how many times
+// LookupTXT is actually invoked, for asserting cache/coalescing
+// behavior.
+type countingResolver struct {
+	mockResolver
+	calls int64
+}
+
+func (c *countingResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	atomic.AddInt64(&c.calls, 1)
+	return c.mockResolver.LookupTXT(ctx, name)
+}
+
+func TestCachingHesiodHitsAndMisses(t *testing.T) {
+	inner := &countingResolver{mockResolver: mockResolver(map[string][]string{
+		"zephyr.sloc.ns.athena.mit.edu": {"ARILINN.MIT.EDU"},
+	})}
+	hesiod := NewCachingHesiod(AthenaConfig, CacheOptions{DefaultTTL: time.Minute})
+	hesiod.lookup.(*ttlCache).inner = inner
+
+	if _, err := hesiod.Resolve("zephyr", "sloc"); err != nil {
+		t.FailNow()
+	}
+	if _, err := hesiod.Resolve("zephyr", "sloc"); err != nil {
+		t.FailNow()
+	}
+	if inner.calls != 1 {
+		t.Fail()
+	}
+	stats := hesiod.Stats()
+	if stats.Hits != 1 || stats.Misses != 1 {
+		t.Fail()
+	}
+}
+
+func TestCachingHesiodExpiry(t *testing.T) {
+	inner := &countingResolver{mockResolver: mockResolver(map[string][]string{
+		"zephyr.sloc.ns.athena.mit.edu": {"ARILINN.MIT.EDU"},
+	})}
+	hesiod := NewCachingHesiod(AthenaConfig, CacheOptions{DefaultTTL: time.Nanosecond})
+	hesiod.lookup.(*ttlCache).inner = inner
+
+	if _, err := hesiod.Resolve("zephyr", "sloc"); err != nil {
+		t.FailNow()
+	}
+	time.Sleep(time.Millisecond)
+	if _, err := hesiod.Resolve("zephyr", "sloc"); err != nil {
+		t.FailNow()
+	}
+	if inner.calls != 2 {
+		t.Fail()
+	}
+}
+
+func TestCachingHesiodNegativeCache(t *testing.T) {
+	inner := &countingResolver{mockResolver: mockResolver(map[string][]string{})}
+	hesiod := NewCachingHesiod(AthenaConfig, CacheOptions{DefaultTTL: time.Minute, NegativeTTL: time.Minute})
+	hesiod.lookup.(*ttlCache).inner = inner
+
+	if _, err := hesiod.Resolve("zephyr", "sloc"); err == nil {
+		t.FailNow()
+	}
+	if _, err := hesiod.Resolve("zephyr", "sloc"); err == nil {
+		t.FailNow()
+	}
+	if inner.calls != 1 {
+		t.Fail()
+	}
+}
+
+func TestCachingHesiodPurge(t *testing.T) {
+	inner := &countingResolver{mockResolver: mockResolver(map[string][]string{
+		"zephyr.sloc.ns.athena.mit.edu": {"ARILINN.MIT.EDU"},
+	})}
+	hesiod := NewCachingHesiod(AthenaConfig, CacheOptions{DefaultTTL: time.Minute})
+	hesiod.lookup.(*ttlCache).inner = inner
+
+	if _, err := hesiod.Resolve("zephyr", "sloc"); err != nil {
+		t.FailNow()
+	}
+	if err := hesiod.Purge("zephyr", "sloc"); err != nil {
+		t.FailNow()
+	}
+	if _, err := hesiod.Resolve("zephyr", "sloc"); err != nil {
+		t.FailNow()
+	}
+	if inner.calls != 2 {
+		t.Fail()
+	}
+}
+
+func TestCachingHesiodMaxEntriesEviction(t *testing.T) {
+	inner := &countingResolver{mockResolver: mockResolver(map[string][]string{
+		"a.sloc.ns.athena.mit.edu": {"A"},
+		"b.sloc.ns.athena.mit.edu": {"B"},
+	})}
+	hesiod := NewCachingHesiod(AthenaConfig, CacheOptions{DefaultTTL: time.Minute, MaxEntries: 1})
+	hesiod.lookup.(*ttlCache).inner = inner
+
+	if _, err := hesiod.Resolve("a", "sloc"); err != nil {
+		t.FailNow()
+	}
+	if _, err := hesiod.Resolve("b", "sloc"); err != nil {
+		t.FailNow()
+	}
+	if hesiod.Stats().Evictions != 1 {
+		t.Fail()
+	}
+	// "a" should have been evicted, so resolving it again re-queries.
+	if _, err := hesiod.Resolve("a", "sloc"); err != nil {
+		t.FailNow()
+	}
+	if inner.calls != 3 {
+		t.Fail()
+	}
+}
+
+func TestCachingHesiodCoalescesConcurrentLookups(t *testing.T) {
+	inner := &countingResolver{mockResolver: mockResolver(map[string][]string{
+		"zephyr.sloc.ns.athena.mit.edu": {"ARILINN.MIT.EDU"},
+	})}
+	hesiod := NewCachingHesiod(AthenaConfig, CacheOptions{DefaultTTL: time.Minute})
+	hesiod.lookup.(*ttlCache).inner = inner
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := hesiod.Resolve("zephyr", "sloc"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if inner.calls != 1 {
+		t.Fail()
+	}
+}
+
+// slowResolver delays every LookupTXT by delay before delegating to
+// mockResolver, to exercise single-flight behavior around
+// cancellation.
+type slowResolver struct {
+	mockResolver
+	delay time.Duration
+}
+
+func (s *slowResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	time.Sleep(s.delay)
+	return s.mockResolver.LookupTXT(ctx, name)
+}
+
+func TestCachingHesiodIsolatesCallerContexts(t *testing.T) {
+	inner := &slowResolver{
+		mockResolver: mockResolver(map[string][]string{
+			"zephyr.sloc.ns.athena.mit.edu": {"ARILINN.MIT.EDU"},
+		}),
+		delay: 100 * time.Millisecond,
+	}
+	hesiod := NewCachingHesiod(AthenaConfig, CacheOptions{DefaultTTL: time.Minute, NegativeTTL: time.Minute})
+	hesiod.lookup.(*ttlCache).inner = inner
+
+	var wg sync.WaitGroup
+	var goodErr error
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		hesiod.ResolveContext(ctx, "zephyr", "sloc")
+	}()
+	go func() {
+		defer wg.Done()
+		_, goodErr = hesiod.ResolveContext(context.Background(), "zephyr", "sloc")
+	}()
+	wg.Wait()
+
+	if goodErr != nil {
+		t.Fatalf("caller with context.Background() got an error from an unrelated caller's cancellation: %v", goodErr)
+	}
+
+	// The successful result, not a cancellation error, must be what
+	// got cached.
+	txt, err := hesiod.Resolve("zephyr", "sloc")
+	if err != nil {
+		t.Fatalf("result was poisoned by a cancelled caller's error: %v", err)
+	}
+	if len(txt) != 1 || txt[0] != "ARILINN.MIT.EDU" {
+		t.Fail()
+	}
+}
+
+func TestCachingHesiodHonorsCallerDeadlineOnCacheMiss(t *testing.T) {
+	inner := &slowResolver{
+		mockResolver: mockResolver(map[string][]string{
+			"zephyr.sloc.ns.athena.mit.edu": {"ARILINN.MIT.EDU"},
+		}),
+		delay: 200 * time.Millisecond,
+	}
+	hesiod := NewCachingHesiod(AthenaConfig, CacheOptions{DefaultTTL: time.Minute})
+	hesiod.lookup.(*ttlCache).inner = inner
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := hesiod.ResolveContext(ctx, "zephyr", "sloc")
+	elapsed := time.Since(start)
+
+	if err != context.DeadlineExceeded {
+		t.Fatalf("got err = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed >= inner.delay {
+		t.Fatalf("ResolveContext blocked for %v, a lone caller's own deadline was not honored", elapsed)
+	}
+
+	// The background lookup keeps running and still populates the
+	// cache for the next caller.
+	time.Sleep(2 * inner.delay)
+	txt, err := hesiod.Resolve("zephyr", "sloc")
+	if err != nil {
+		t.Fatalf("background lookup did not populate the cache: %v", err)
+	}
+	if len(txt) != 1 || txt[0] != "ARILINN.MIT.EDU" {
+		t.Fail()
+	}
+}
+
+func TestPurgeNoOpWithoutCache(t *testing.T) {
+	hesiod := NewHesiodWithConfig(AthenaConfig)
+	hesiod.lookup = mockResolver(map[string][]string{})
+	if err := hesiod.Purge("zephyr", "sloc"); err != nil {
+		t.Fail()
+	}
+	if hesiod.Stats() != (CacheStats{}) {
+		t.Fail()
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright 2014 The hesiod-go authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command hesinfo is a command-line Hesiod client, mirroring the
+// interface of the classic Athena hesinfo(1):
+//
+//	hesinfo [-b] [-l] HESIOD-NAME HESIOD-TYPE
+//	hesinfo service NAME PROTO
+//	hesinfo passwd USER
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	hesiod "github.com/zephyr-im/hesiod-go"
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: %s [flags] [-b] [-l] HESIOD-NAME HESIOD-TYPE\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "       %s [flags] service NAME PROTO\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "       %s [flags] passwd USER\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "\nflags:\n")
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+func main() {
+	flag.Usage = usage
+	dumpBytes := flag.Bool("b", false, "show each decoded TXT record as a Go-quoted string; the raw DNS wire bytes are not shown")
+	printName := flag.Bool("l", false, "print the fully-qualified DNS name being queried")
+	configPath := flag.String("config", "", "path to a hesiod.conf-format configuration file (default: HESIOD_CONFIG or /etc/hesiod.conf)")
+	realm := flag.String("realm", "", "override the Hesiod realm (rhs)")
+	lhs := flag.String("lhs", "", "override the Hesiod nameserver prefix (lhs)")
+	jsonOutput := flag.Bool("json", false, "emit output as JSON")
+	flag.Parse()
+
+	h, err := newHesiod(*configPath, *lhs, *realm)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "hesinfo:", err)
+		os.Exit(1)
+	}
+
+	args := flag.Args()
+	if len(args) == 0 {
+		usage()
+	}
+
+	var runErr error
+	switch args[0] {
+	case "service":
+		if len(args) != 3 {
+			usage()
+		}
+		runErr = runService(h, args[1], args[2], *jsonOutput)
+	case "passwd":
+		if len(args) != 2 {
+			usage()
+		}
+		runErr = runPasswd(h, args[1], *jsonOutput)
+	default:
+		if len(args) != 2 {
+			usage()
+		}
+		runErr = runResolve(h, args[0], args[1], *dumpBytes, *printName, *jsonOutput)
+	}
+	if runErr != nil {
+		fmt.Fprintln(os.Stderr, "hesinfo:", runErr)
+		os.Exit(1)
+	}
+}
+
+// newHesiod builds a Hesiod resolver from the given configuration
+// file (or HESIOD_CONFIG/HES_DOMAIN/DefaultHesiodConfigFile if
+// configPath is empty), then applies any --lhs/--realm overrides on
+// top.
+func newHesiod(configPath, lhs, realm string) (*hesiod.Hesiod, error) {
+	var h *hesiod.Hesiod
+	var err error
+	if configPath != "" {
+		h, err = hesiod.NewHesiodFromFile(configPath)
+	} else {
+		h, err = hesiod.NewHesiodFromEnv()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if lhs == "" && realm == "" {
+		return h, nil
+	}
+	cfg := h.Config()
+	if lhs != "" {
+		cfg.Nameserver = lhs
+	}
+	if realm != "" {
+		cfg.Realm = realm
+	}
+	return hesiod.NewHesiodWithConfig(cfg), nil
+}
+
+func runResolve(h *hesiod.Hesiod, name, queryType string, dumpBytes, printName, jsonOutput bool) error {
+	txt, err := h.Resolve(name, queryType)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		out := struct {
+			DNSName string   `json:"dns_name,omitempty"`
+			Records []string `json:"records"`
+		}{Records: txt}
+		if printName {
+			if out.DNSName, err = h.DNSName(name, queryType); err != nil {
+				return err
+			}
+		}
+		return printJSON(out)
+	}
+
+	if printName {
+		dns, err := h.DNSName(name, queryType)
+		if err != nil {
+			return err
+		}
+		fmt.Println(dns)
+	}
+	for _, record := range txt {
+		if dumpBytes {
+			// net.Resolver.LookupTXT only exposes decoded TXT
+			// strings, not the raw wire bytes, so this quotes the
+			// decoded string instead of dumping the literal DNS
+			// record.
+			fmt.Println(strconv.Quote(record))
+		} else {
+			fmt.Println(record)
+		}
+	}
+	return nil
+}
+
+func runService(h *hesiod.Hesiod, name, proto string, jsonOutput bool) error {
+	entry, err := h.GetServiceByName(name, proto)
+	if err != nil {
+		return err
+	}
+	if jsonOutput {
+		return printJSON(entry)
+	}
+	fmt.Printf("%s %s %d\n", entry.ServiceName, entry.Protocol, entry.Port)
+	return nil
+}
+
+func runPasswd(h *hesiod.Hesiod, user string, jsonOutput bool) error {
+	entry, err := h.GetPasswdByName(user)
+	if err != nil {
+		return err
+	}
+	if jsonOutput {
+		return printJSON(entry)
+	}
+	fmt.Printf("%s:*:%d:%d:%s:%s:%s\n", entry.Name, entry.UID, entry.GID, entry.GECOS, entry.Dir, entry.Shell)
+	return nil
+}
+
+func printJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
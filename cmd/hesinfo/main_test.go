@@ -0,0 +1,83 @@
+// Copyright 2014 The hesiod-go authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewHesiodDefaultsToEnv(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "hesiod.conf")
+	if err := os.WriteFile(path, []byte("lhs=.ns\nrhs=.athena.mit.edu\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("HESIOD_CONFIG", path)
+	h, err := newHesiod("", "", "")
+	if err != nil {
+		t.FailNow()
+	}
+	cfg := h.Config()
+	if cfg.Nameserver != ".ns" || cfg.Realm != ".athena.mit.edu" {
+		t.Fail()
+	}
+}
+
+func TestNewHesiodConfigOverride(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hesiod.conf")
+	if err := os.WriteFile(path, []byte("lhs=.ns2\nrhs=.example.com\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	h, err := newHesiod(path, "", "")
+	if err != nil {
+		t.FailNow()
+	}
+	cfg := h.Config()
+	if cfg.Nameserver != ".ns2" || cfg.Realm != ".example.com" {
+		t.Fail()
+	}
+}
+
+func TestNewHesiodLhsRealmOverrideComposesWithConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hesiod.conf")
+	if err := os.WriteFile(path, []byte("lhs=.ns2\nrhs=.example.com\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	h, err := newHesiod(path, ".ns3", ".sipb.mit.edu")
+	if err != nil {
+		t.FailNow()
+	}
+	cfg := h.Config()
+	if cfg.Nameserver != ".ns3" || cfg.Realm != ".sipb.mit.edu" {
+		t.Fail()
+	}
+}
+
+func TestNewHesiodRealmOnlyOverrideKeepsConfigLhs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hesiod.conf")
+	if err := os.WriteFile(path, []byte("lhs=.ns2\nrhs=.example.com\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	h, err := newHesiod(path, "", ".sipb.mit.edu")
+	if err != nil {
+		t.FailNow()
+	}
+	cfg := h.Config()
+	if cfg.Nameserver != ".ns2" || cfg.Realm != ".sipb.mit.edu" {
+		t.Fail()
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2014 The hesiod-go authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hesiod
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// parseConfig parses a Hesiod configuration file in the lhs=/rhs=
+// format used by /etc/hesiod.conf (see hesiod(3) in the C library
+// for the canonical format). Blank lines and lines beginning with
+// '#' are ignored; any other line must be a whitespace-tolerant
+// key=value pair naming a recognized key, or parseConfig returns an
+// error.
+func parseConfig(r io.Reader) (*Config, error) {
+	config := &Config{}
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("hesiod: config line %d is not a key=value pair: %q", lineNumber, line)
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		switch key {
+		case "lhs":
+			config.Nameserver = value
+		case "rhs":
+			config.Realm = value
+		default:
+			return nil, fmt.Errorf("hesiod: config line %d has unknown key %q", lineNumber, key)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("hesiod: reading config: %w", err)
+	}
+	return config, nil
+}
+
+// NewHesiodFromFile initializes a Hesiod resolver from the
+// lhs=/rhs= configuration file at path.
+func NewHesiodFromFile(path string) (*Hesiod, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	config, err := parseConfig(f)
+	if err != nil {
+		return nil, fmt.Errorf("hesiod: parsing %s: %w", path, err)
+	}
+	return NewHesiodWithConfig(config), nil
+}
+
+// NewHesiodFromEnv initializes a Hesiod resolver honoring the
+// HESIOD_CONFIG and HES_DOMAIN environment variables recognized by
+// the C Hesiod library. HESIOD_CONFIG, if set, names a
+// configuration file to read in place of DefaultHesiodConfigFile;
+// HES_DOMAIN, if set, overrides the realm (rhs) from that
+// configuration. NewHesiod does not consult the environment;
+// NewHesiodFromEnv is an opt-in alternative for callers that want
+// the C library's behavior.
+//
+// A missing DefaultHesiodConfigFile falls back to AthenaConfig, the
+// same as NewHesiod. An explicitly set HESIOD_CONFIG is assumed to
+// be deliberate, so a missing or malformed file it names is
+// reported as an error rather than silently falling back.
+func NewHesiodFromEnv() (*Hesiod, error) {
+	path := os.Getenv("HESIOD_CONFIG")
+	explicit := path != ""
+	if !explicit {
+		path = DefaultHesiodConfigFile
+	}
+	hesiod, err := NewHesiodFromFile(path)
+	if err != nil {
+		if explicit || !os.IsNotExist(err) {
+			return nil, err
+		}
+		hesiod = NewHesiodWithConfig(AthenaConfig)
+	}
+	if domain := os.Getenv("HES_DOMAIN"); domain != "" {
+		hesiod.realm = "." + strings.TrimPrefix(domain, ".")
+	}
+	return hesiod, nil
+}
@@ -0,0 +1,92 @@
+// Copyright 2014 The hesiod-go authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hesiod
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseConfig(t *testing.T) {
+	config, err := parseConfig(strings.NewReader("# a comment\n\nlhs=.ns\nrhs=.athena.mit.edu\n"))
+	if err != nil {
+		t.FailNow()
+	}
+	if config.Nameserver != ".ns" || config.Realm != ".athena.mit.edu" {
+		t.Fail()
+	}
+}
+
+func TestParseConfigUnknownKey(t *testing.T) {
+	_, err := parseConfig(strings.NewReader("lhs=.ns\nbogus=1\n"))
+	if err == nil {
+		t.FailNow()
+	}
+}
+
+func TestParseConfigMalformedLine(t *testing.T) {
+	_, err := parseConfig(strings.NewReader("this has no equals\nthis is not key=value, wait it is\n"))
+	if err == nil {
+		t.FailNow()
+	}
+}
+
+func TestNewHesiodFromFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "hesiod.conf")
+	if err := os.WriteFile(path, []byte("lhs=.ns\nrhs=.sipb.mit.edu\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	hesiod, err := NewHesiodFromFile(path)
+	if err != nil {
+		t.FailNow()
+	}
+	if hesiod.nameserver != ".ns" || hesiod.realm != ".sipb.mit.edu" {
+		t.Fail()
+	}
+}
+
+func TestNewHesiodFromFileMissing(t *testing.T) {
+	_, err := NewHesiodFromFile(filepath.Join(t.TempDir(), "does-not-exist"))
+	if err == nil {
+		t.FailNow()
+	}
+}
+
+func TestNewHesiodFromEnv(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "hesiod.conf")
+	if err := os.WriteFile(path, []byte("lhs=.ns\nrhs=.athena.mit.edu\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("HESIOD_CONFIG", path)
+	t.Setenv("HES_DOMAIN", "sipb.mit.edu")
+	hesiod, err := NewHesiodFromEnv()
+	if err != nil {
+		t.FailNow()
+	}
+	if hesiod.nameserver != ".ns" || hesiod.realm != ".sipb.mit.edu" {
+		t.Fail()
+	}
+}
+
+func TestNewHesiodFromEnvExplicitConfigMissing(t *testing.T) {
+	t.Setenv("HESIOD_CONFIG", filepath.Join(t.TempDir(), "does-not-exist"))
+	if _, err := NewHesiodFromEnv(); err == nil {
+		t.Fatal("expected an error for a missing, explicitly-set HESIOD_CONFIG")
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright 2014 The hesiod-go authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hesiod
+
+import "fmt"
+
+// A FieldError records the failure to parse a single field of a
+// Hesiod record returned by one of the typed accessors, such as
+// GetPasswdByName or GetFilsys.
+type FieldError struct {
+	// Record identifies the kind of record being parsed, e.g.
+	// "passwd", "group", or "filsys".
+	Record string
+	// Field is the name of the field that failed to parse.
+	Field string
+	// Err is the underlying parse error.
+	Err error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("hesiod: parsing %s field %q: %v", e.Record, e.Field, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,110 @@
+// Copyright 2014 The hesiod-go authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hesiod
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// FilsysType identifies the kind of record returned for a Hesiod
+// "filsys" query, mirroring the type tags used by the C Hesiod
+// library's filsys parser.
+type FilsysType string
+
+// The filsys record types understood by GetFilsys.
+const (
+	FilsysAFS FilsysType = "AFS"
+	FilsysNFS FilsysType = "NFS"
+	FilsysUFS FilsysType = "UFS"
+	FilsysErr FilsysType = "ERR"
+	FilsysLoc FilsysType = "LOC"
+)
+
+// FilsysEntry is a single record returned by GetFilsys, describing
+// how to mount a filesystem. Which fields are meaningful depends on
+// Type: NFS entries set Host and RemotePath instead of Location,
+// and ERR entries set only Message.
+type FilsysEntry struct {
+	Type FilsysType
+	// Location is the AFS path or UFS device; unset for NFS and ERR.
+	Location string
+	// Host and RemotePath are the NFS server and export path; only
+	// set when Type is FilsysNFS.
+	Host       string
+	RemotePath string
+	Mode       string
+	Mountpoint string
+	// Message is the human-readable text of an ERR record; only set
+	// when Type is FilsysErr.
+	Message string
+}
+
+// GetFilsys queries Hesiod for the filesystem records of the given
+// name, as nss_hesiod's hesiod-service.c does for the "filsys"
+// query type. A name may legitimately have several filsys records;
+// GetFilsys preserves the order in which they were returned.
+// GetFilsys is equivalent to GetFilsysContext with
+// context.Background().
+func (hesiod *Hesiod) GetFilsys(name string) ([]FilsysEntry, error) {
+	return hesiod.GetFilsysContext(context.Background(), name)
+}
+
+// GetFilsysContext is like GetFilsys, but honors ctx for
+// cancellation and deadlines on the underlying DNS lookup.
+func (hesiod *Hesiod) GetFilsysContext(ctx context.Context, name string) ([]FilsysEntry, error) {
+	records, err := hesiod.ResolveContext(ctx, name, "filsys")
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]FilsysEntry, 0, len(records))
+	for _, record := range records {
+		entry, err := parseFilsysEntry(record)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func parseFilsysEntry(record string) (FilsysEntry, error) {
+	fields := strings.Fields(record)
+	if len(fields) < 2 {
+		return FilsysEntry{}, &FieldError{Record: "filsys", Field: "record", Err: fmt.Errorf("expected at least a type and a value, got %q", record)}
+	}
+	filsysType := FilsysType(fields[0])
+	switch filsysType {
+	case FilsysErr:
+		return FilsysEntry{Type: filsysType, Message: strings.Join(fields[1:], " ")}, nil
+	case FilsysNFS:
+		if len(fields) != 4 {
+			return FilsysEntry{}, &FieldError{Record: "filsys", Field: "record", Err: fmt.Errorf("NFS record wants 4 fields, got %d", len(fields))}
+		}
+		host, remotePath, ok := strings.Cut(fields[1], ":")
+		if !ok {
+			return FilsysEntry{}, &FieldError{Record: "filsys", Field: "location", Err: fmt.Errorf("NFS location %q is not host:path", fields[1])}
+		}
+		return FilsysEntry{Type: filsysType, Host: host, RemotePath: remotePath, Mode: fields[2], Mountpoint: fields[3]}, nil
+	case FilsysAFS, FilsysUFS, FilsysLoc:
+		if len(fields) != 4 {
+			return FilsysEntry{}, &FieldError{Record: "filsys", Field: "record", Err: fmt.Errorf("%s record wants 4 fields, got %d", filsysType, len(fields))}
+		}
+		return FilsysEntry{Type: filsysType, Location: fields[1], Mode: fields[2], Mountpoint: fields[3]}, nil
+	default:
+		return FilsysEntry{}, &FieldError{Record: "filsys", Field: "type", Err: fmt.Errorf("unknown filsys type %q", fields[0])}
+	}
+}
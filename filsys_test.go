@@ -0,0 +1,79 @@
+// Copyright 2014 The hesiod-go authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hesiod
+
+import "testing"
+
+func TestGetFilsysNFS(t *testing.T) {
+	hesiod := NewHesiodWithConfig(AthenaConfig)
+	hesiod.lookup = mockResolver(map[string][]string{
+		"achernya.filsys.ns.athena.mit.edu": {
+			"NFS SERVER.MIT.EDU:/mit/achernya w /mit/achernya",
+		},
+	})
+	entries, err := hesiod.GetFilsys("achernya")
+	if err != nil {
+		t.FailNow()
+	}
+	if len(entries) != 1 {
+		t.FailNow()
+	}
+	entry := entries[0]
+	if entry.Type != FilsysNFS || entry.Host != "SERVER.MIT.EDU" || entry.RemotePath != "/mit/achernya" || entry.Mountpoint != "/mit/achernya" {
+		t.Fail()
+	}
+}
+
+func TestGetFilsysMultipleRecordsPreserveOrder(t *testing.T) {
+	hesiod := NewHesiodWithConfig(AthenaConfig)
+	hesiod.lookup = mockResolver(map[string][]string{
+		"achernya.filsys.ns.athena.mit.edu": {
+			"AFS /mit/achernya w /mit/achernya",
+			"NFS SERVER.MIT.EDU:/mit/achernya w /mit/achernya2",
+		},
+	})
+	entries, err := hesiod.GetFilsys("achernya")
+	if err != nil {
+		t.FailNow()
+	}
+	if len(entries) != 2 || entries[0].Type != FilsysAFS || entries[1].Type != FilsysNFS {
+		t.Fail()
+	}
+}
+
+func TestGetFilsysErr(t *testing.T) {
+	hesiod := NewHesiodWithConfig(AthenaConfig)
+	hesiod.lookup = mockResolver(map[string][]string{
+		"achernya.filsys.ns.athena.mit.edu": {"ERR no filsys for achernya"},
+	})
+	entries, err := hesiod.GetFilsys("achernya")
+	if err != nil {
+		t.FailNow()
+	}
+	if entries[0].Type != FilsysErr || entries[0].Message != "no filsys for achernya" {
+		t.Fail()
+	}
+}
+
+func TestGetFilsysUnknownType(t *testing.T) {
+	hesiod := NewHesiodWithConfig(AthenaConfig)
+	hesiod.lookup = mockResolver(map[string][]string{
+		"achernya.filsys.ns.athena.mit.edu": {"XFS /dev/sda1 w /mit/achernya"},
+	})
+	_, err := hesiod.GetFilsys("achernya")
+	if err == nil {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2014 The hesiod-go authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hesiod
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// GroupEntry contains the information similar to struct group from
+// <grp.h>, and is returned by GetGroupByName and GetGroupByGID.
+type GroupEntry struct {
+	Name    string
+	GID     int
+	Members []string
+}
+
+// GetGroupByName queries Hesiod for the group entry of the named
+// group, as nss_hesiod's hesiod-grp.c does for getgrnam(3).
+// GetGroupByName is equivalent to GetGroupByNameContext with
+// context.Background().
+func (hesiod *Hesiod) GetGroupByName(group string) (GroupEntry, error) {
+	return hesiod.GetGroupByNameContext(context.Background(), group)
+}
+
+// GetGroupByNameContext is like GetGroupByName, but honors ctx for
+// cancellation and deadlines on the underlying DNS lookup.
+func (hesiod *Hesiod) GetGroupByNameContext(ctx context.Context, group string) (GroupEntry, error) {
+	records, err := hesiod.ResolveContext(ctx, group, "group")
+	if err != nil {
+		return GroupEntry{}, err
+	}
+	if len(records) == 0 {
+		return GroupEntry{}, fmt.Errorf("hesiod: no group record for %q", group)
+	}
+	return parseGroupEntry(records[0])
+}
+
+// GetGroupByGID queries Hesiod for the group entry with the given
+// GID, as nss_hesiod's hesiod-grp.c does for getgrgid(3). The "gid"
+// query type redirects to the group's name, which is then resolved
+// as in GetGroupByName. GetGroupByGID is equivalent to
+// GetGroupByGIDContext with context.Background().
+func (hesiod *Hesiod) GetGroupByGID(gid int) (GroupEntry, error) {
+	return hesiod.GetGroupByGIDContext(context.Background(), gid)
+}
+
+// GetGroupByGIDContext is like GetGroupByGID, but honors ctx for
+// cancellation and deadlines on the underlying DNS lookups.
+func (hesiod *Hesiod) GetGroupByGIDContext(ctx context.Context, gid int) (GroupEntry, error) {
+	names, err := hesiod.ResolveContext(ctx, strconv.Itoa(gid), "gid")
+	if err != nil {
+		return GroupEntry{}, err
+	}
+	if len(names) == 0 {
+		return GroupEntry{}, fmt.Errorf("hesiod: no gid record for %d", gid)
+	}
+	return hesiod.GetGroupByNameContext(ctx, names[0])
+}
+
+func parseGroupEntry(record string) (GroupEntry, error) {
+	fields := strings.Split(record, ":")
+	if len(fields) != 4 {
+		return GroupEntry{}, &FieldError{Record: "group", Field: "record", Err: fmt.Errorf("expected 4 colon-separated fields, got %d", len(fields))}
+	}
+	gid, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return GroupEntry{}, &FieldError{Record: "group", Field: "gid", Err: err}
+	}
+	var members []string
+	if fields[3] != "" {
+		members = strings.Split(fields[3], ",")
+	}
+	return GroupEntry{
+		Name:    fields[0],
+		GID:     gid,
+		Members: members,
+	}, nil
+}
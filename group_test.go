@@ -0,0 +1,85 @@
+// Copyright 2014 The hesiod-go authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hesiod
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGetGroupByName(t *testing.T) {
+	hesiod := NewHesiodWithConfig(AthenaConfig)
+	hesiod.lookup = mockResolver(map[string][]string{
+		"sipb.group.ns.athena.mit.edu": {"sipb:*:101:achernya,quentin"},
+	})
+	entry, err := hesiod.GetGroupByName("sipb")
+	if err != nil {
+		t.FailNow()
+	}
+	if entry.Name != "sipb" || entry.GID != 101 || strings.Join(entry.Members, ",") != "achernya,quentin" {
+		t.Fail()
+	}
+}
+
+func TestGetGroupByGID(t *testing.T) {
+	hesiod := NewHesiodWithConfig(AthenaConfig)
+	hesiod.lookup = mockResolver(map[string][]string{
+		"101.gid.ns.athena.mit.edu":    {"sipb"},
+		"sipb.group.ns.athena.mit.edu": {"sipb:*:101:achernya,quentin"},
+	})
+	entry, err := hesiod.GetGroupByGID(101)
+	if err != nil {
+		t.FailNow()
+	}
+	if entry.Name != "sipb" || entry.GID != 101 {
+		t.Fail()
+	}
+}
+
+func TestGetGroupByNameEmptyMembers(t *testing.T) {
+	hesiod := NewHesiodWithConfig(AthenaConfig)
+	hesiod.lookup = mockResolver(map[string][]string{
+		"sipb.group.ns.athena.mit.edu": {"sipb:*:101:"},
+	})
+	entry, err := hesiod.GetGroupByName("sipb")
+	if err != nil {
+		t.FailNow()
+	}
+	if len(entry.Members) != 0 {
+		t.Fail()
+	}
+}
+
+func TestGetGroupByNameNoRecords(t *testing.T) {
+	hesiod := NewHesiodWithConfig(AthenaConfig)
+	hesiod.lookup = mockResolver(map[string][]string{
+		"sipb.group.ns.athena.mit.edu": {},
+	})
+	_, err := hesiod.GetGroupByName("sipb")
+	if err == nil {
+		t.FailNow()
+	}
+}
+
+func TestGetGroupByGIDNoRecords(t *testing.T) {
+	hesiod := NewHesiodWithConfig(AthenaConfig)
+	hesiod.lookup = mockResolver(map[string][]string{
+		"101.gid.ns.athena.mit.edu": {},
+	})
+	_, err := hesiod.GetGroupByGID(101)
+	if err == nil {
+		t.FailNow()
+	}
+}
@@ -24,8 +24,10 @@
 package hesiod
 
 import (
+	"context"
 	"fmt"
 	"net"
+	"os"
 	"strings"
 )
 
@@ -35,6 +37,12 @@ import (
 type Config struct {
 	Nameserver string
 	Realm string
+	// Resolver, if set, is used to perform the underlying DNS TXT
+	// lookups, in place of net.DefaultResolver. This is useful when
+	// Hesiod records live on a nameserver that isn't in
+	// /etc/resolv.conf: bind a *net.Resolver to it with a custom
+	// Dial and set it here.
+	Resolver *net.Resolver
 }
 
 // AthenaConfig is the default configuration for Hesiod, for use at
@@ -52,6 +60,9 @@ type Hesiod struct {
 	realm string
 	// For testing only
 	lookup lookupInterface
+	// The *net.Resolver backing lookup, kept around so Config can
+	// report it even after lookup is wrapped by NewCachingHesiod.
+	resolver *net.Resolver
 }
 
 // DefaultHesiodConfigFile is a path to the systemd-wide hesiod.conf
@@ -67,26 +78,39 @@ type ServiceEntry struct {
 }
 
 // NewHesiod initializes a Hesiod resolver with the machine's local
-// Hesiod configuration.
-func NewHesiod() *Hesiod {
-	// Hesiod, being created at MIT, officially defaults to MIT's
-	// ATHENA realm unless a configuration file is specified.
-	//
-	// TODO(achernya): Implement parsing hesiod.conf and make that
-	// the default.
-	return NewHesiodWithConfig(AthenaConfig)
+// Hesiod configuration, read from DefaultHesiodConfigFile. Hesiod,
+// being created at MIT, officially defaults to MIT's ATHENA realm,
+// so if no configuration file is present, NewHesiod falls back to
+// AthenaConfig.
+func NewHesiod() (*Hesiod, error) {
+	hesiod, err := NewHesiodFromFile(DefaultHesiodConfigFile)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+		return NewHesiodWithConfig(AthenaConfig), nil
+	}
+	return hesiod, nil
 }
 
 // NewHesiodWithConfig allows the application to override the
 // machine's local Hesiod configuration with the specified
 // configuration. In the C Hesiod library, this was controlled through
-// the HESIOD_CONFIG environment variable, which is intentionally
-// unsupported in hesiod-go.
+// the HESIOD_CONFIG environment variable; hesiod-go instead exposes
+// that as the opt-in NewHesiodFromEnv constructor.
 func NewHesiodWithConfig(config *Config) *Hesiod {
-	return &Hesiod{config.Nameserver, config.Realm, lookupInterfaceFunc(net.LookupTXT)}
+	resolver := config.Resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	return &Hesiod{config.Nameserver, config.Realm, resolver, resolver}
 }
 
-func (hesiod *Hesiod) prepareDNSName(question string, queryType string) (dns string, err error) {
+func (hesiod *Hesiod) prepareDNSName(question string, queryType string) (string, error) {
+	return hesiod.prepareDNSNameContext(context.Background(), question, queryType)
+}
+
+func (hesiod *Hesiod) prepareDNSNameContext(ctx context.Context, question string, queryType string) (dns string, err error) {
 	splitQuestion := strings.SplitN(question, "@", 2)
 	realm := hesiod.realm
 	if len(splitQuestion) == 2 {
@@ -98,7 +122,7 @@ func (hesiod *Hesiod) prepareDNSName(question string, queryType string) (dns str
 		realm = splitQuestion[1]
 		if !strings.ContainsRune(realm, '.') {
 			var candidates []string
-			candidates, err = hesiod.Resolve(realm, "rhs-extension")
+			candidates, err = hesiod.ResolveContext(ctx, realm, "rhs-extension")
 			if err != nil {
 				return
 			}
@@ -110,24 +134,60 @@ func (hesiod *Hesiod) prepareDNSName(question string, queryType string) (dns str
 	return
 }
 
+// Config returns the lhs/rhs configuration that hesiod was
+// constructed with, for composing with other configuration sources
+// (for example, applying command-line overrides on top of a parsed
+// hesiod.conf).
+func (hesiod *Hesiod) Config() *Config {
+	return &Config{Nameserver: hesiod.nameserver, Realm: hesiod.realm, Resolver: hesiod.resolver}
+}
+
+// DNSName returns the fully-qualified DNS name that Resolve would
+// query for the given question and queryType, without performing
+// the lookup. This is useful for diagnostics such as hesinfo -l.
+// DNSName is equivalent to DNSNameContext with context.Background().
+func (hesiod *Hesiod) DNSName(question string, queryType string) (string, error) {
+	return hesiod.prepareDNSName(question, queryType)
+}
+
+// DNSNameContext is like DNSName, but honors ctx for cancellation
+// and deadlines on any redirect lookup needed to resolve the realm.
+func (hesiod *Hesiod) DNSNameContext(ctx context.Context, question string, queryType string) (string, error) {
+	return hesiod.prepareDNSNameContext(ctx, question, queryType)
+}
+
 // Resolve performs the Hesiod resolution of the given query and its
 // type. For example, try Resolve("hesiod", "sloc") or
 // Resolve("achernya", "passwd") with the Athena configuration.
-func (hesiod *Hesiod) Resolve(question string, queryType string) (txt []string, err error) {
+// Resolve is equivalent to ResolveContext with context.Background().
+func (hesiod *Hesiod) Resolve(question string, queryType string) ([]string, error) {
+	return hesiod.ResolveContext(context.Background(), question, queryType)
+}
+
+// ResolveContext is like Resolve, but honors ctx for cancellation
+// and deadlines on the underlying DNS lookup.
+func (hesiod *Hesiod) ResolveContext(ctx context.Context, question string, queryType string) (txt []string, err error) {
 	var dns string
-	dns, err = hesiod.prepareDNSName(question, queryType)
+	dns, err = hesiod.prepareDNSNameContext(ctx, question, queryType)
 	if err != nil {
 		return
 	}
-	txt, err = hesiod.lookup.LookupTXT(dns)
+	txt, err = hesiod.lookup.LookupTXT(ctx, dns)
 	return
 }
 
 // GetServiceByName queries Hesiod for the service information
-// (protocol, port).
-func (hesiod *Hesiod) GetServiceByName(service string, proto string) (result ServiceEntry, err error) {
+// (protocol, port). GetServiceByName is equivalent to
+// GetServiceByNameContext with context.Background().
+func (hesiod *Hesiod) GetServiceByName(service string, proto string) (ServiceEntry, error) {
+	return hesiod.GetServiceByNameContext(context.Background(), service, proto)
+}
+
+// GetServiceByNameContext is like GetServiceByName, but honors ctx
+// for cancellation and deadlines on the underlying DNS lookup.
+func (hesiod *Hesiod) GetServiceByNameContext(ctx context.Context, service string, proto string) (result ServiceEntry, err error) {
 	var candidates []string
-	candidates, err = hesiod.Resolve(service, "service")
+	candidates, err = hesiod.ResolveContext(ctx, service, "service")
 	for _, candidate := range candidates {
 		var resolvedService, protocol string
 		var port int
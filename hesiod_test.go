@@ -15,7 +15,9 @@
 package hesiod
 
 import (
+	"context"
 	"errors"
+	"net"
 	"strings"
 	"testing"
 )
@@ -42,7 +44,7 @@ func TestPrepareDNSName(t *testing.T) {
 
 type mockResolver map[string][]string
 
-func (mock mockResolver) LookupTXT(name string) ([]string, error) {
+func (mock mockResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
 	value, ok := mock[name]
 	if !ok {
 		return nil, errors.New("Mock resolver cannot find specified value")
@@ -107,3 +109,11 @@ func TestGetServiceByName(t *testing.T) {
 
 
 }
+
+func TestNewHesiodWithConfigCustomResolver(t *testing.T) {
+	resolver := &net.Resolver{}
+	hesiod := NewHesiodWithConfig(&Config{Nameserver: AthenaConfig.Nameserver, Realm: AthenaConfig.Realm, Resolver: resolver})
+	if hesiod.lookup.(*net.Resolver) != resolver {
+		t.Fail()
+	}
+}
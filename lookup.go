@@ -0,0 +1,25 @@
+// Copyright 2014 The hesiod-go authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hesiod
+
+import "context"
+
+// lookupInterface abstracts the DNS TXT lookup used to resolve
+// Hesiod queries, so that it can be swapped out in tests. Its
+// signature matches (*net.Resolver).LookupTXT, so a *net.Resolver
+// satisfies it directly.
+type lookupInterface interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
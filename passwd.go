@@ -0,0 +1,100 @@
+// Copyright 2014 The hesiod-go authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hesiod
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PasswdEntry contains the information similar to struct passwd
+// from <pwd.h>, and is returned by GetPasswdByName and
+// GetPasswdByUID.
+type PasswdEntry struct {
+	Name  string
+	UID   int
+	GID   int
+	GECOS string
+	Dir   string
+	Shell string
+}
+
+// GetPasswdByName queries Hesiod for the passwd entry of the named
+// user, as nss_hesiod's hesiod-pwd.c does for getpwnam(3).
+// GetPasswdByName is equivalent to GetPasswdByNameContext with
+// context.Background().
+func (hesiod *Hesiod) GetPasswdByName(user string) (PasswdEntry, error) {
+	return hesiod.GetPasswdByNameContext(context.Background(), user)
+}
+
+// GetPasswdByNameContext is like GetPasswdByName, but honors ctx
+// for cancellation and deadlines on the underlying DNS lookup.
+func (hesiod *Hesiod) GetPasswdByNameContext(ctx context.Context, user string) (PasswdEntry, error) {
+	records, err := hesiod.ResolveContext(ctx, user, "passwd")
+	if err != nil {
+		return PasswdEntry{}, err
+	}
+	if len(records) == 0 {
+		return PasswdEntry{}, fmt.Errorf("hesiod: no passwd record for %q", user)
+	}
+	return parsePasswdEntry(records[0])
+}
+
+// GetPasswdByUID queries Hesiod for the passwd entry of the user
+// with the given UID, as nss_hesiod's hesiod-pwd.c does for
+// getpwuid(3). The "uid" query type redirects to the user's name,
+// which is then resolved as in GetPasswdByName. GetPasswdByUID is
+// equivalent to GetPasswdByUIDContext with context.Background().
+func (hesiod *Hesiod) GetPasswdByUID(uid int) (PasswdEntry, error) {
+	return hesiod.GetPasswdByUIDContext(context.Background(), uid)
+}
+
+// GetPasswdByUIDContext is like GetPasswdByUID, but honors ctx for
+// cancellation and deadlines on the underlying DNS lookups.
+func (hesiod *Hesiod) GetPasswdByUIDContext(ctx context.Context, uid int) (PasswdEntry, error) {
+	names, err := hesiod.ResolveContext(ctx, strconv.Itoa(uid), "uid")
+	if err != nil {
+		return PasswdEntry{}, err
+	}
+	if len(names) == 0 {
+		return PasswdEntry{}, fmt.Errorf("hesiod: no uid record for %d", uid)
+	}
+	return hesiod.GetPasswdByNameContext(ctx, names[0])
+}
+
+func parsePasswdEntry(record string) (PasswdEntry, error) {
+	fields := strings.Split(record, ":")
+	if len(fields) != 7 {
+		return PasswdEntry{}, &FieldError{Record: "passwd", Field: "record", Err: fmt.Errorf("expected 7 colon-separated fields, got %d", len(fields))}
+	}
+	uid, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return PasswdEntry{}, &FieldError{Record: "passwd", Field: "uid", Err: err}
+	}
+	gid, err := strconv.Atoi(fields[3])
+	if err != nil {
+		return PasswdEntry{}, &FieldError{Record: "passwd", Field: "gid", Err: err}
+	}
+	return PasswdEntry{
+		Name:  fields[0],
+		UID:   uid,
+		GID:   gid,
+		GECOS: fields[4],
+		Dir:   fields[5],
+		Shell: fields[6],
+	}, nil
+}
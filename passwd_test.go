@@ -0,0 +1,83 @@
+// Copyright 2014 The hesiod-go authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hesiod
+
+import "testing"
+
+func TestGetPasswdByName(t *testing.T) {
+	hesiod := NewHesiodWithConfig(AthenaConfig)
+	hesiod.lookup = mockResolver(map[string][]string{
+		"achernya.passwd.ns.athena.mit.edu": {
+			"achernya:*:1234:101:Andrew Chernyakhovsky,,,:/mit/achernya:/bin/bash",
+		},
+	})
+	entry, err := hesiod.GetPasswdByName("achernya")
+	if err != nil {
+		t.FailNow()
+	}
+	if entry.Name != "achernya" || entry.UID != 1234 || entry.GID != 101 || entry.Shell != "/bin/bash" {
+		t.Fail()
+	}
+}
+
+func TestGetPasswdByUID(t *testing.T) {
+	hesiod := NewHesiodWithConfig(AthenaConfig)
+	hesiod.lookup = mockResolver(map[string][]string{
+		"1234.uid.ns.athena.mit.edu": {"achernya"},
+		"achernya.passwd.ns.athena.mit.edu": {
+			"achernya:*:1234:101:Andrew Chernyakhovsky,,,:/mit/achernya:/bin/bash",
+		},
+	})
+	entry, err := hesiod.GetPasswdByUID(1234)
+	if err != nil {
+		t.FailNow()
+	}
+	if entry.Name != "achernya" || entry.UID != 1234 {
+		t.Fail()
+	}
+}
+
+func TestGetPasswdByNameMalformed(t *testing.T) {
+	hesiod := NewHesiodWithConfig(AthenaConfig)
+	hesiod.lookup = mockResolver(map[string][]string{
+		"achernya.passwd.ns.athena.mit.edu": {"not:enough:fields"},
+	})
+	_, err := hesiod.GetPasswdByName("achernya")
+	if err == nil {
+		t.FailNow()
+	}
+}
+
+func TestGetPasswdByNameNoRecords(t *testing.T) {
+	hesiod := NewHesiodWithConfig(AthenaConfig)
+	hesiod.lookup = mockResolver(map[string][]string{
+		"achernya.passwd.ns.athena.mit.edu": {},
+	})
+	_, err := hesiod.GetPasswdByName("achernya")
+	if err == nil {
+		t.FailNow()
+	}
+}
+
+func TestGetPasswdByUIDNoRecords(t *testing.T) {
+	hesiod := NewHesiodWithConfig(AthenaConfig)
+	hesiod.lookup = mockResolver(map[string][]string{
+		"1234.uid.ns.athena.mit.edu": {},
+	})
+	_, err := hesiod.GetPasswdByUID(1234)
+	if err == nil {
+		t.FailNow()
+	}
+}